@@ -0,0 +1,184 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hypershift reconciles PerformanceProfiles for HyperShift hosted
+// clusters. On a hosted cluster the PerformanceProfile does not exist as a
+// CR - it is stored as a ConfigMap in the hosted-control-plane namespace on
+// the management cluster, and the generated MachineConfig/KubeletConfig/Tuned
+// manifests are written back into a single output ConfigMap for NodePool to
+// consume, instead of being applied to a cluster directly.
+package hypershift
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+
+	performancev2 "github.com/openshift-kni/performance-addon-operators/api/v2"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+	profilestatus "github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/status"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// PerformanceProfileConfigMapLabelKey marks a ConfigMap on the
+	// management cluster as the source of truth for a hosted
+	// PerformanceProfile.
+	PerformanceProfileConfigMapLabelKey = "hypershift.openshift.io/performanceprofile"
+
+	// performanceProfileDataKey is the ConfigMap data key holding the
+	// serialized PerformanceProfileSpec.
+	performanceProfileDataKey = "config"
+
+	// componentsDataKey is the output ConfigMap data key holding the
+	// rendered manifests that NodePool consumes. Deliberately distinct
+	// from performanceProfileDataKey so the two never collide if the
+	// input and output ConfigMaps are ever merged into one.
+	componentsDataKey = "components"
+)
+
+// ConfigMapReconciler reconciles PerformanceProfiles that live as labeled
+// ConfigMaps in the hosted-control-plane namespace on the management
+// cluster. It renders the same manifests the self-managed
+// PerformanceProfileReconciler does (via AssetsDir), but instead of applying
+// MachineConfig/KubeletConfig/Tuned objects to a cluster, it serializes them
+// into a single output ConfigMap next to the input one.
+type ConfigMapReconciler struct {
+	// Client talks to the management cluster, where the input and output
+	// ConfigMaps live.
+	Client    client.Client
+	Scheme    *runtime.Scheme
+	Recorder  record.EventRecorder
+	AssetsDir string
+
+	// HostedClusterNamespace is the hosted-control-plane namespace on the
+	// management cluster that holds the PerformanceProfile ConfigMaps.
+	HostedClusterNamespace string
+
+	// StatusWriter surfaces aggregated conditions for the profile. In this
+	// mode it is expected to be a ConfigMapWriter, since there is no CR to
+	// patch a status onto.
+	StatusWriter profilestatus.Writer
+}
+
+// SetupWithManager registers the controller on mgr, but watches ConfigMaps
+// on managementCluster rather than on mgr's own cluster, since that is
+// where the input and output ConfigMaps live.
+func (r *ConfigMapReconciler) SetupWithManager(mgr ctrl.Manager, managementCluster cluster.Cluster) error {
+	c, err := controller.New("performanceprofile-hypershift-configmap", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	return c.Watch(source.Kind(managementCluster.GetCache(), &corev1.ConfigMap{}), &handler.EnqueueRequestForObject{})
+}
+
+func (r *ConfigMapReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if req.Namespace != r.HostedClusterNamespace {
+		return ctrl.Result{}, nil
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, req.NamespacedName, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if cm.Labels[PerformanceProfileConfigMapLabelKey] == "" {
+		// not one of ours
+		return ctrl.Result{}, nil
+	}
+
+	profile, err := r.decodeProfile(cm)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed decoding PerformanceProfile from ConfigMap %s/%s: %w", cm.Namespace, cm.Name, err)
+	}
+
+	renderedComponents, err := components.GetComponents(profile, r.AssetsDir)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed rendering components for PerformanceProfile %q: %w", profile.Name, err)
+	}
+
+	payload, err := yaml.Marshal(renderedComponents)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed serializing rendered components for PerformanceProfile %q: %w", profile.Name, err)
+	}
+
+	output := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      outputConfigMapName(cm.Name),
+			Namespace: r.HostedClusterNamespace,
+		},
+	}
+
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, output, func() error {
+		if output.Data == nil {
+			output.Data = map[string]string{}
+		}
+		output.Data[componentsDataKey] = string(payload)
+		return controllerutil.SetOwnerReference(cm, output, r.Scheme)
+	}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed writing output ConfigMap for PerformanceProfile %q: %w", profile.Name, err)
+	}
+
+	if r.StatusWriter != nil {
+		condition := metav1.Condition{
+			Type:    "Available",
+			Status:  metav1.ConditionTrue,
+			Reason:  "AsExpected",
+			Message: "rendered components written",
+		}
+		if err := r.StatusWriter.Update(ctx, profile, []metav1.Condition{condition}); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed writing status for PerformanceProfile %q: %w", profile.Name, err)
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *ConfigMapReconciler) decodeProfile(cm *corev1.ConfigMap) (*performancev2.PerformanceProfile, error) {
+	raw, ok := cm.Data[performanceProfileDataKey]
+	if !ok {
+		return nil, fmt.Errorf("ConfigMap is missing the %q data key", performanceProfileDataKey)
+	}
+
+	profile := &performancev2.PerformanceProfile{}
+	if err := yaml.Unmarshal([]byte(raw), &profile.Spec); err != nil {
+		return nil, err
+	}
+	profile.Name = cm.Name
+
+	return profile, nil
+}
+
+func outputConfigMapName(inputName string) string {
+	return fmt.Sprintf("%s-%s", inputName, "components")
+}
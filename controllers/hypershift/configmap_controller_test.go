@@ -0,0 +1,72 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hypershift
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+
+	performancev2 "github.com/openshift-kni/performance-addon-operators/api/v2"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDecodeProfileRoundTrip(t *testing.T) {
+	spec := performancev2.PerformanceProfileSpec{
+		RealTimeKernel: &performancev2.RealTimeKernel{Enabled: boolPtr(true)},
+	}
+
+	payload, err := yaml.Marshal(spec)
+	if err != nil {
+		t.Fatalf("failed marshaling PerformanceProfileSpec: %v", err)
+	}
+
+	cm := &corev1.ConfigMap{
+		Data: map[string]string{performanceProfileDataKey: string(payload)},
+	}
+	cm.Name = "worker-profile"
+
+	profile, err := (&ConfigMapReconciler{}).decodeProfile(cm)
+	if err != nil {
+		t.Fatalf("decodeProfile returned error: %v", err)
+	}
+
+	if profile.Name != cm.Name {
+		t.Errorf("profile.Name = %q, want %q", profile.Name, cm.Name)
+	}
+	if profile.Spec.RealTimeKernel == nil || profile.Spec.RealTimeKernel.Enabled == nil || !*profile.Spec.RealTimeKernel.Enabled {
+		t.Errorf("profile.Spec.RealTimeKernel = %+v, want RealTimeKernel.Enabled=true", profile.Spec.RealTimeKernel)
+	}
+}
+
+func TestDecodeProfileMissingDataKey(t *testing.T) {
+	cm := &corev1.ConfigMap{}
+	cm.Name = "worker-profile"
+
+	if _, err := (&ConfigMapReconciler{}).decodeProfile(cm); err == nil {
+		t.Error("expected an error when the ConfigMap is missing the performanceProfileDataKey")
+	}
+}
+
+func TestOutputConfigMapName(t *testing.T) {
+	if got, want := outputConfigMapName("worker-profile"), "worker-profile-components"; got != want {
+		t.Errorf("outputConfigMapName() = %q, want %q", got, want)
+	}
+}
+
+func boolPtr(b bool) *bool { return &b }
@@ -0,0 +1,367 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package controllers reconciles PerformanceProfiles on a self-managed
+// cluster: the CR itself is the source of truth, and the rendered
+// MachineConfig/KubeletConfig/Tuned manifests are applied directly to this
+// cluster, unlike controllers/hypershift which renders into a ConfigMap.
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	performancev2 "github.com/openshift-kni/performance-addon-operators/api/v2"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+	profilestatus "github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/status"
+	"github.com/openshift-kni/performance-addon-operators/pkg/mcps"
+	statusmcps "github.com/openshift-kni/performance-addon-operators/pkg/status/mcps"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	machineconfigv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	conditionAvailable                 = "Available"
+	conditionDegraded                  = "Degraded"
+	conditionProgressing               = "Progressing"
+	conditionExpectedRolloutCompletion = "ExpectedRolloutCompletionTime"
+	conditionReasonUnknown             = "Unknown"
+
+	// statusRateLimitRequeueDelay is how long Reconcile waits before retrying
+	// a status write that StatusWriter denied, matching the QPS=1 token
+	// bucket rateLimitedWriter is configured with today.
+	statusRateLimitRequeueDelay = time.Second
+)
+
+// PerformanceProfileReconciler reconciles a PerformanceProfile CR on a
+// self-managed cluster: it renders manifests from the CR spec, applies them
+// directly, and aggregates their rollout status back onto the CR via
+// StatusWriter.
+type PerformanceProfileReconciler struct {
+	Client    client.Client
+	Scheme    *runtime.Scheme
+	Recorder  record.EventRecorder
+	AssetsDir string
+
+	// StatusWriter surfaces the profile's aggregated conditions. On a
+	// self-managed cluster this is a CRWriter patching the CR's own
+	// .status.
+	StatusWriter profilestatus.Writer
+}
+
+// SetupWithManager registers the controller on mgr. It does not own the
+// generated MachineConfigPool or Tuned Profile - applyComponents never sets
+// a controller reference on either, since they are shared pool/node objects
+// rather than profile-owned ones - so their rollout status is instead
+// watched explicitly and mapped back to the owning PerformanceProfile.
+func (r *PerformanceProfileReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&performancev2.PerformanceProfile{}).
+		Watches(&machineconfigv1.MachineConfigPool{}, handler.EnqueueRequestsFromMapFunc(r.mcpToProfile)).
+		Watches(&tunedv1.Profile{}, handler.EnqueueRequestsFromMapFunc(r.tunedProfileToProfile)).
+		Complete(r)
+}
+
+// mcpToProfile maps a MachineConfigPool to the PerformanceProfile(s) whose
+// generated MachineConfig it carries as a source, so a pool going
+// Updating/Degraded re-triggers reconciliation of the owning profile.
+func (r *PerformanceProfileReconciler) mcpToProfile(ctx context.Context, obj client.Object) []reconcile.Request {
+	mcp, ok := obj.(*machineconfigv1.MachineConfigPool)
+	if !ok {
+		return nil
+	}
+
+	profiles := &performancev2.PerformanceProfileList{}
+	if err := r.Client.List(ctx, profiles); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range profiles.Items {
+		sourceName := fmt.Sprintf("%s-%s", components.ComponentNamePrefix, profiles.Items[i].Name)
+		if mcpHasSource(mcp, sourceName) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: profiles.Items[i].Name}})
+		}
+	}
+
+	return requests
+}
+
+// tunedProfileToProfile maps a Tuned Profile to the PerformanceProfile(s)
+// whose generated Tuned CR it is configured against, so a node profile
+// failing to apply re-triggers reconciliation of the owning profile.
+func (r *PerformanceProfileReconciler) tunedProfileToProfile(ctx context.Context, obj client.Object) []reconcile.Request {
+	nodeProfile, ok := obj.(*tunedv1.Profile)
+	if !ok {
+		return nil
+	}
+
+	profiles := &performancev2.PerformanceProfileList{}
+	if err := r.Client.List(ctx, profiles); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range profiles.Items {
+		tunedProfileName := fmt.Sprintf("%s-%s", components.ComponentNamePrefix, profiles.Items[i].Name)
+		if nodeProfile.Spec.Config.TunedProfile == tunedProfileName {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: profiles.Items[i].Name}})
+		}
+	}
+
+	return requests
+}
+
+func (r *PerformanceProfileReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	profile := &performancev2.PerformanceProfile{}
+	if err := r.Client.Get(ctx, req.NamespacedName, profile); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	renderedComponents, err := components.GetComponents(profile, r.AssetsDir)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed rendering components for PerformanceProfile %q: %w", profile.Name, err)
+	}
+
+	if err := r.applyComponents(ctx, profile, renderedComponents); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if r.StatusWriter != nil {
+		conditions, err := r.aggregateConditions(ctx, profile)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed aggregating status for PerformanceProfile %q: %w", profile.Name, err)
+		}
+		if err := r.StatusWriter.Update(ctx, profile, conditions); err != nil {
+			if errors.Is(err, profilestatus.ErrRateLimited) {
+				// The write was denied, not failed - nothing else will
+				// re-trigger reconciliation for it, so requeue ourselves
+				// after the limiter's delay instead of dropping it.
+				return ctrl.Result{RequeueAfter: statusRateLimitRequeueDelay}, nil
+			}
+			return ctrl.Result{}, fmt.Errorf("failed writing status for PerformanceProfile %q: %w", profile.Name, err)
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// applyComponents creates or updates each rendered manifest, owned by
+// profile so it is cleaned up when the profile is deleted.
+func (r *PerformanceProfileReconciler) applyComponents(ctx context.Context, profile *performancev2.PerformanceProfile, objs []client.Object) error {
+	for _, obj := range objs {
+		if err := controllerutil.SetControllerReference(profile, obj, r.Scheme); err != nil {
+			return fmt.Errorf("failed setting owner reference on %T %q: %w", obj, obj.GetName(), err)
+		}
+
+		if err := r.Client.Create(ctx, obj); err != nil {
+			if !apierrors.IsAlreadyExists(err) {
+				return fmt.Errorf("failed creating %T %q: %w", obj, obj.GetName(), err)
+			}
+			if err := r.Client.Update(ctx, obj); err != nil {
+				return fmt.Errorf("failed updating %T %q: %w", obj, obj.GetName(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// newCondition builds a metav1.Condition with a guaranteed non-empty
+// Reason - the apiserver requires and pattern-validates Reason, but a
+// Reason copied straight from an MCP/Tuned source condition can be empty -
+// and no LastTransitionTime; callers merge it via meta.SetStatusCondition,
+// which fills that in.
+func newCondition(conditionType string, status metav1.ConditionStatus, reason, message string) metav1.Condition {
+	if reason == "" {
+		reason = conditionReasonUnknown
+	}
+	return metav1.Condition{Type: conditionType, Status: status, Reason: reason, Message: message}
+}
+
+// aggregateConditions rolls the rollout status of the profile's generated
+// MachineConfigPool source and Tuned profile up into the single condition
+// set StatusWriter publishes, so a degraded node rollout on either is
+// visible on the PerformanceProfile itself. Conditions are merged via
+// meta.SetStatusCondition against profile's existing conditions so that
+// LastTransitionTime is only bumped when a condition's Status actually
+// changes.
+func (r *PerformanceProfileReconciler) aggregateConditions(ctx context.Context, profile *performancev2.PerformanceProfile) ([]metav1.Condition, error) {
+	conditions := append([]metav1.Condition(nil), profile.Status.Conditions...)
+
+	mcpDegraded, mcpReason, mcpMessage, err := r.mcpDegradedCondition(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	if mcpDegraded {
+		meta.SetStatusCondition(&conditions, newCondition(conditionDegraded, metav1.ConditionTrue, mcpReason, mcpMessage))
+		return conditions, nil
+	}
+
+	tunedDegraded, tunedReason, tunedMessage, err := r.tunedDegradedCondition(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	if tunedDegraded {
+		meta.SetStatusCondition(&conditions, newCondition(conditionDegraded, metav1.ConditionTrue, tunedReason, tunedMessage))
+		return conditions, nil
+	}
+	meta.SetStatusCondition(&conditions, newCondition(conditionDegraded, metav1.ConditionFalse, "AsExpected", "no degraded rollout source"))
+
+	progressing, progressingMessage, expectedCompletion, err := r.rolloutProgressingCondition(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	if progressing {
+		meta.SetStatusCondition(&conditions, newCondition(conditionProgressing, metav1.ConditionTrue, "RolloutInProgress", progressingMessage))
+		meta.SetStatusCondition(&conditions, newCondition(conditionExpectedRolloutCompletion, metav1.ConditionTrue, "RolloutInProgress", expectedCompletion))
+		return conditions, nil
+	}
+	meta.SetStatusCondition(&conditions, newCondition(conditionProgressing, metav1.ConditionFalse, "AsExpected", "no rollout in progress"))
+
+	meta.SetStatusCondition(&conditions, newCondition(conditionAvailable, metav1.ConditionTrue, "AsExpected", "components applied"))
+	return conditions, nil
+}
+
+// profileMCP returns the MachineConfigPool carrying profile's generated
+// MachineConfig, or nil if none picked it up yet.
+func (r *PerformanceProfileReconciler) profileMCP(ctx context.Context, profile *performancev2.PerformanceProfile) (*machineconfigv1.MachineConfigPool, error) {
+	mcpList := &machineconfigv1.MachineConfigPoolList{}
+	if err := r.Client.List(ctx, mcpList); err != nil {
+		return nil, fmt.Errorf("failed listing MachineConfigPools: %w", err)
+	}
+
+	sourceName := fmt.Sprintf("%s-%s", components.ComponentNamePrefix, profile.Name)
+	for i := range mcpList.Items {
+		if mcpHasSource(&mcpList.Items[i], sourceName) {
+			return &mcpList.Items[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// mcpDegradedCondition reports whether the MachineConfigPool carrying
+// profile's generated MachineConfig is Degraded.
+func (r *PerformanceProfileReconciler) mcpDegradedCondition(ctx context.Context, profile *performancev2.PerformanceProfile) (degraded bool, reason, message string, err error) {
+	mcp, err := r.profileMCP(ctx, profile)
+	if err != nil {
+		return false, "", "", err
+	}
+
+	if mcp != nil {
+		status, err := statusmcps.GetConditionStatus(ctx, r.Client, mcp.Name, machineconfigv1.MachineConfigPoolDegraded)
+		if err != nil {
+			return false, "", "", err
+		}
+		if status != corev1.ConditionTrue {
+			return false, "", "", nil
+		}
+
+		reason, err := statusmcps.GetConditionReason(ctx, r.Client, mcp.Name, machineconfigv1.MachineConfigPoolDegraded)
+		if err != nil {
+			return false, "", "", err
+		}
+		return true, reason, fmt.Sprintf("MachineConfigPool %q is degraded", mcp.Name), nil
+	}
+
+	return false, "", "", nil
+}
+
+// rolloutProgressingCondition reports whether the MachineConfigPool
+// carrying profile's generated MachineConfig is still updating, and if so
+// how long that rollout is expected to take - computed via
+// pkg/mcps.ExpectedRolloutWindow from the number of nodes it targets - so
+// callers can tell a rollout that is legitimately still running from one
+// that is stuck. expectedCompletion is the RFC3339 timestamp surfaced on
+// the ExpectedRolloutCompletionTime condition and is only meaningful when
+// progressing is true.
+func (r *PerformanceProfileReconciler) rolloutProgressingCondition(ctx context.Context, profile *performancev2.PerformanceProfile) (progressing bool, message, expectedCompletion string, err error) {
+	mcp, err := r.profileMCP(ctx, profile)
+	if err != nil || mcp == nil {
+		return false, "", "", err
+	}
+
+	status, err := statusmcps.GetConditionStatus(ctx, r.Client, mcp.Name, machineconfigv1.MachineConfigPoolUpdating)
+	if err != nil {
+		return false, "", "", err
+	}
+	if status != corev1.ConditionTrue {
+		return false, "", "", nil
+	}
+
+	window, err := mcps.ExpectedRolloutWindow(ctx, r.Client, mcp.Name, mcps.Options{})
+	if err != nil {
+		return false, "", "", fmt.Errorf("failed computing expected rollout window for MachineConfigPool %q: %w", mcp.Name, err)
+	}
+
+	expectedCompletion = time.Now().Add(window).Format(time.RFC3339)
+	return true, fmt.Sprintf("MachineConfigPool %q is rolling out, expected completion by %s", mcp.Name, expectedCompletion), expectedCompletion, nil
+}
+
+func mcpHasSource(mcp *machineconfigv1.MachineConfigPool, sourceName string) bool {
+	for _, source := range mcp.Spec.Configuration.Source {
+		if source.Name == sourceName {
+			return true
+		}
+	}
+	return false
+}
+
+// tunedDegradedCondition reports whether any node's Tuned Profile for
+// profile's generated Tuned CR has failed to apply.
+func (r *PerformanceProfileReconciler) tunedDegradedCondition(ctx context.Context, profile *performancev2.PerformanceProfile) (degraded bool, reason, message string, err error) {
+	tunedProfileName := fmt.Sprintf("%s-%s", components.ComponentNamePrefix, profile.Name)
+
+	nodeProfiles := &tunedv1.ProfileList{}
+	if err := r.Client.List(ctx, nodeProfiles, client.InNamespace(components.NamespaceNodeTuningOperator)); err != nil {
+		return false, "", "", fmt.Errorf("failed listing Tuned Profiles: %w", err)
+	}
+
+	for i := range nodeProfiles.Items {
+		nodeProfile := &nodeProfiles.Items[i]
+		if nodeProfile.Spec.Config.TunedProfile != tunedProfileName {
+			continue
+		}
+
+		for _, condition := range nodeProfile.Status.Conditions {
+			if condition.Type == tunedv1.TunedProfileDegraded && condition.Status == corev1.ConditionTrue {
+				return true, condition.Reason, fmt.Sprintf("Tuned profile %q is degraded on node %q", tunedProfileName, nodeProfile.Name), nil
+			}
+		}
+	}
+
+	return false, "", "", nil
+}
@@ -0,0 +1,82 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhooktls keeps the webhook server's TLS configuration in sync
+// with the cluster-scoped APIServer config.openshift.io resource.
+package webhooktls
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-kni/performance-addon-operators/pkg/webhook/tlsconfig"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// apiServerResourceName is the name of the cluster-scoped APIServer
+// singleton.
+const apiServerResourceName = "cluster"
+
+// APIServerReconciler watches the cluster APIServer config and pushes its
+// TLSSecurityProfile into a tlsconfig.Manager, so the webhook server picks
+// up policy changes without a pod restart.
+type APIServerReconciler struct {
+	Client client.Client
+
+	// Manager is updated in place with every observed TLSSecurityProfile.
+	Manager *tlsconfig.Manager
+
+	// FallbackProfile is applied when the APIServer resource does not
+	// exist (e.g. in functests), overridden by --tls-min-version /
+	// --tls-cipher-suites.
+	FallbackProfile *configv1.TLSSecurityProfile
+}
+
+// SetupWithManager registers the controller against the APIServer
+// singleton.
+func (r *APIServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&configv1.APIServer{}).
+		Named("performanceprofile-webhook-tls").
+		Complete(r)
+}
+
+func (r *APIServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if req.Name != apiServerResourceName {
+		return ctrl.Result{}, nil
+	}
+
+	apiServer := &configv1.APIServer{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: apiServerResourceName}, apiServer)
+	switch {
+	case apierrors.IsNotFound(err):
+		return ctrl.Result{}, r.Manager.Set(r.FallbackProfile)
+	case err != nil:
+		return ctrl.Result{}, fmt.Errorf("failed getting APIServer %q: %w", apiServerResourceName, err)
+	}
+
+	if err := r.Manager.Set(apiServer.Spec.TLSSecurityProfile); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed applying TLSSecurityProfile from APIServer %q: %w", apiServerResourceName, err)
+	}
+
+	return ctrl.Result{}, nil
+}
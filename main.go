@@ -17,23 +17,41 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"os"
 	"runtime"
+	"strings"
+	"time"
 
 	performancev1 "github.com/openshift-kni/performance-addon-operators/api/v1"
 	performancev1alpha1 "github.com/openshift-kni/performance-addon-operators/api/v1alpha1"
 	performancev2 "github.com/openshift-kni/performance-addon-operators/api/v2"
 	"github.com/openshift-kni/performance-addon-operators/controllers"
+	"github.com/openshift-kni/performance-addon-operators/controllers/hypershift"
+	"github.com/openshift-kni/performance-addon-operators/controllers/webhooktls"
 	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+	profilestatus "github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/status"
 	"github.com/openshift-kni/performance-addon-operators/pkg/utils/leaderelection"
+	"github.com/openshift-kni/performance-addon-operators/pkg/webhook/tlsconfig"
 	"github.com/openshift-kni/performance-addon-operators/version"
 	"github.com/spf13/cobra"
 
+	configv1 "github.com/openshift/api/config/v1"
 	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
 	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/util/flowcontrol"
+	logsapi "k8s.io/component-base/logs/api/v1"
+	_ "k8s.io/component-base/logs/json/register"
 	"k8s.io/klog"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -46,16 +64,20 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
-	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
 	// +kubebuilder:scaffold:imports
 )
 
 const (
-	leaderElectionID = "performance-addon-operators" // Autogenerated plural form
-	webhookPort      = 4343
-	webhookCertDir   = "/apiserver.local.config/certificates"
-	webhookCertName  = "apiserver.crt"
-	webhookKeyName   = "apiserver.key"
+	leaderElectionID        = "performance-addon-operators" // Autogenerated plural form
+	leaderElectionNamespace = "openshift-performance-addon-operator"
+	webhookPort             = 4343
+	webhookCertDir          = "/apiserver.local.config/certificates"
+	webhookCertName         = "apiserver.crt"
+	webhookKeyName          = "apiserver.key"
+	leaseReleaseTimeout     = 10 * time.Second
+	statusWriteQPS          = 1
+	statusWriteBurst        = 5
 )
 
 // Change below variables to serve metrics on different host or port.
@@ -74,6 +96,7 @@ func init() {
 	utilruntime.Must(performancev1alpha1.AddToScheme(scheme))
 	utilruntime.Must(performancev1.AddToScheme(scheme))
 	utilruntime.Must(performancev2.AddToScheme(scheme))
+	utilruntime.Must(configv1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -85,6 +108,65 @@ func printVersion() {
 	klog.Infof("Go OS/Arch: %s/%s", runtime.GOOS, runtime.GOARCH)
 }
 
+// newLeaderElectionIdentity builds this process's leader-election identity
+// up front, the same way client-go's resourcelock package would generate
+// one for us (hostname + a random UUID suffix), so we can hand it to our
+// own resourcelock.Interface and compare against it later in
+// releaseLeaderLease instead of trying to recover an opaque,
+// library-generated identity after the fact.
+func newLeaderElectionIdentity() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return hostname + "_" + string(uuid.NewUUID())
+}
+
+// releaseLeaderLease clears spec.holderIdentity on the leader-election Lease
+// we hold so the next pod doesn't have to wait out the full LeaseDuration
+// before it can take over. It is a best-effort step: if the lease is no
+// longer held, or is held by a different identity - e.g. a new pod already
+// won the lease by the time this one is exiting during a rolling upgrade -
+// it does nothing, since clearing it would only steal the new leader's
+// lease out from under it.
+func releaseLeaderLease(restConfig *rest.Config, identity string) {
+	ctx, cancel := context.WithTimeout(context.Background(), leaseReleaseTimeout)
+	defer cancel()
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		klog.Errorf("failed to release leader-election lease: %v", err)
+		return
+	}
+
+	leases := clientset.CoordinationV1().Leases(leaderElectionNamespace)
+	lease, err := leases.Get(ctx, leaderElectionID, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		klog.Info("leader-election lease not found, nothing to release")
+		return
+	}
+	if err != nil {
+		klog.Errorf("failed to get leader-election lease %s/%s: %v", leaderElectionNamespace, leaderElectionID, err)
+		return
+	}
+
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != identity {
+		klog.Info("leader-election lease is no longer held by this instance, skipping release")
+		return
+	}
+
+	lease.Spec.HolderIdentity = nil
+	shortDuration := int32(1)
+	lease.Spec.LeaseDurationSeconds = &shortDuration
+
+	if _, err := leases.Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+		klog.Errorf("failed to release leader-election lease: %v", err)
+		return
+	}
+
+	klog.Info("released leader-election lease on shutdown")
+}
+
 func main() {
 	// Add klog flags
 	klog.InitFlags(nil)
@@ -96,33 +178,75 @@ func main() {
 }
 
 func newRootCommand() *cobra.Command {
+	loggingConfig := logsapi.NewLoggingConfiguration()
+	loggingConfig.Verbosity = logsapi.VerbosityLevel(2)
+
 	cmd := &cobra.Command{
 		Use:   "performance-addon-operator",
 		Short: "OpenShift performance addon operator",
 		Run: func(cmd *cobra.Command, args []string) {
 			// if no subcommand just run the usual
-			runPAO()
+			runPAO(loggingConfig)
 		},
 	}
+	logsapi.AddFlags(loggingConfig, cmd.Flags())
+
+	renderCmd := render.NewRenderCommand()
+	logsapi.AddFlags(loggingConfig, renderCmd.Flags())
+	renderPreRunE := renderCmd.PreRunE
+	renderCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := logsapi.ValidateAndApply(loggingConfig, nil); err != nil {
+			return err
+		}
+		ctrl.SetLogger(klog.NewKlogr())
+		if renderPreRunE != nil {
+			return renderPreRunE(cmd, args)
+		}
+		return nil
+	}
+	cmd.AddCommand(renderCmd)
 
-	cmd.AddCommand(render.NewRenderCommand())
 	return cmd
 }
 
-func runPAO() {
+func runPAO(loggingConfig *logsapi.LoggingConfiguration) {
 	var metricsAddr string
 	var enableLeaderElection bool
-
-	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
+	var hypershiftMode bool
+	var managementKubeconfig string
+	var hostedClusterNamespace string
+	var tlsMinVersion string
+	var tlsCipherSuites string
 
 	flag.StringVar(&metricsAddr, "metrics-addr", fmt.Sprintf("%s:%d", metricsHost, metricsPort),
 		"The address the metric endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", true,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&hypershiftMode, "hypershift", false,
+		"Run against a HyperShift hosted cluster: PerformanceProfiles are read from, and rendered "+
+			"components are written to, ConfigMaps on the management cluster instead of being "+
+			"reconciled as CRs on this cluster.")
+	flag.StringVar(&managementKubeconfig, "management-kubeconfig", "",
+		"Path to the kubeconfig of the management cluster holding the PerformanceProfile ConfigMaps. "+
+			"Required when --hypershift is set.")
+	flag.StringVar(&hostedClusterNamespace, "hosted-cluster-namespace", "",
+		"Hosted-control-plane namespace on the management cluster holding the PerformanceProfile "+
+			"ConfigMaps. Required when --hypershift is set.")
+	flag.StringVar(&tlsMinVersion, "tls-min-version", string(configv1.VersionTLS12),
+		"Minimum TLS version for the webhook server, used when the cluster-scoped APIServer "+
+			"resource is absent (e.g. functests).")
+	flag.StringVar(&tlsCipherSuites, "tls-cipher-suites", "",
+		"Comma-separated list of cipher suites for the webhook server, used when the cluster-scoped "+
+			"APIServer resource is absent (e.g. functests). Defaults to the Intermediate profile's ciphers.")
 
 	flag.Parse()
 
+	if err := logsapi.ValidateAndApply(loggingConfig, nil); err != nil {
+		klog.Exitf("invalid logging configuration: %v", err)
+	}
+	ctrl.SetLogger(klog.NewKlogr())
+
 	printVersion()
 
 	// we have two namespaces that we need to watch
@@ -136,15 +260,28 @@ func runPAO() {
 	restConfig := ctrl.GetConfigOrDie()
 	le := leaderelection.GetLeaderElectionConfig(restConfig, enableLeaderElection)
 
+	leaderElectionIdentity := newLeaderElectionIdentity()
+
+	var resourceLock resourcelock.Interface
+	if enableLeaderElection {
+		lock, err := resourcelock.NewFromKubeconfig(resourcelock.LeasesResourceLock, leaderElectionNamespace, leaderElectionID,
+			resourcelock.ResourceLockConfig{Identity: leaderElectionIdentity}, restConfig, le.RenewDeadline.Duration)
+		if err != nil {
+			klog.Exitf("unable to create leader-election resource lock: %v", err)
+		}
+		resourceLock = lock
+	}
+
 	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
-		NewCache:           cache.MultiNamespacedCacheBuilder(namespaces),
-		Scheme:             scheme,
-		MetricsBindAddress: metricsAddr,
-		LeaderElection:     enableLeaderElection,
-		LeaderElectionID:   leaderElectionID,
-		LeaseDuration:      &le.LeaseDuration.Duration,
-		RetryPeriod:        &le.RetryPeriod.Duration,
-		RenewDeadline:      &le.RenewDeadline.Duration,
+		NewCache:                            cache.MultiNamespacedCacheBuilder(namespaces),
+		Scheme:                              scheme,
+		MetricsBindAddress:                  metricsAddr,
+		LeaderElection:                      enableLeaderElection,
+		LeaderElectionID:                    leaderElectionID,
+		LeaderElectionResourceLockInterface: resourceLock,
+		LeaseDuration:                       &le.LeaseDuration.Duration,
+		RetryPeriod:                         &le.RetryPeriod.Duration,
+		RenewDeadline:                       &le.RenewDeadline.Duration,
 	})
 
 	if err != nil {
@@ -159,34 +296,139 @@ func runPAO() {
 		klog.Exit(err.Error())
 	}
 
-	if err = (&controllers.PerformanceProfileReconciler{
-		Client:    mgr.GetClient(),
-		Scheme:    mgr.GetScheme(),
-		Recorder:  mgr.GetEventRecorderFor("performance-profile-controller"),
-		AssetsDir: components.AssetsDir,
-	}).SetupWithManager(mgr); err != nil {
-		klog.Exitf("unable to create PerformanceProfile controller : %v", err)
-	}
-
-	// configure webhook server
-	webHookServer := mgr.GetWebhookServer()
-	webHookServer.Port = webhookPort
-	webHookServer.CertDir = webhookCertDir
-	webHookServer.CertName = webhookCertName
-	webHookServer.KeyName = webhookKeyName
-
-	if err = (&performancev1.PerformanceProfile{}).SetupWebhookWithManager(mgr); err != nil {
-		klog.Exitf("unable to create PerformanceProfile v1 webhook : %v", err)
-	}
-	if err = (&performancev2.PerformanceProfile{}).SetupWebhookWithManager(mgr); err != nil {
-		klog.Exitf("unable to create PerformanceProfile v2 webhook : %v", err)
+	if hypershiftMode {
+		if managementKubeconfig == "" || hostedClusterNamespace == "" {
+			klog.Exit("--management-kubeconfig and --hosted-cluster-namespace are required when --hypershift is set")
+		}
+
+		managementConfig, err := clientcmd.BuildConfigFromFlags("", managementKubeconfig)
+		if err != nil {
+			klog.Exitf("unable to load management cluster kubeconfig: %v", err)
+		}
+
+		managementCluster, err := cluster.New(managementConfig, func(o *cluster.Options) {
+			o.Scheme = scheme
+		})
+		if err != nil {
+			klog.Exitf("unable to create management cluster client: %v", err)
+		}
+
+		if err := mgr.Add(managementCluster); err != nil {
+			klog.Exitf("unable to register management cluster with the manager: %v", err)
+		}
+
+		statusWriter := profilestatus.NewRateLimited(&profilestatus.ConfigMapWriter{
+			Client:    managementCluster.GetClient(),
+			Namespace: hostedClusterNamespace,
+		}, flowcontrol.NewTokenBucketRateLimiter(statusWriteQPS, statusWriteBurst))
+
+		if err = (&hypershift.ConfigMapReconciler{
+			Client:                 managementCluster.GetClient(),
+			Scheme:                 mgr.GetScheme(),
+			Recorder:               mgr.GetEventRecorderFor("performance-profile-hypershift-controller"),
+			AssetsDir:              components.AssetsDir,
+			HostedClusterNamespace: hostedClusterNamespace,
+			StatusWriter:           statusWriter,
+		}).SetupWithManager(mgr, managementCluster); err != nil {
+			klog.Exitf("unable to create PerformanceProfile HyperShift controller : %v", err)
+		}
+	} else {
+		statusWriter := profilestatus.NewRateLimited(
+			&profilestatus.CRWriter{Client: mgr.GetClient()},
+			flowcontrol.NewTokenBucketRateLimiter(statusWriteQPS, statusWriteBurst),
+		)
+
+		if err = (&controllers.PerformanceProfileReconciler{
+			Client:       mgr.GetClient(),
+			Scheme:       mgr.GetScheme(),
+			Recorder:     mgr.GetEventRecorderFor("performance-profile-controller"),
+			AssetsDir:    components.AssetsDir,
+			StatusWriter: statusWriter,
+		}).SetupWithManager(mgr); err != nil {
+			klog.Exitf("unable to create PerformanceProfile controller : %v", err)
+		}
+
+		// configure webhook server
+		webHookServer := mgr.GetWebhookServer()
+		webHookServer.Port = webhookPort
+		webHookServer.CertDir = webhookCertDir
+		webHookServer.CertName = webhookCertName
+		webHookServer.KeyName = webhookKeyName
+
+		fallbackCiphers := configv1.TLSProfiles[configv1.TLSProfileIntermediateType].Ciphers
+		if tlsCipherSuites != "" {
+			fallbackCiphers = strings.Split(tlsCipherSuites, ",")
+		}
+		fallbackTLSProfile := &configv1.TLSSecurityProfile{
+			Type: configv1.TLSProfileCustomType,
+			Custom: &configv1.CustomTLSProfile{
+				TLSProfileSpec: configv1.TLSProfileSpec{
+					MinTLSVersion: configv1.TLSProtocolVersion(tlsMinVersion),
+					Ciphers:       fallbackCiphers,
+				},
+			},
+		}
+
+		tlsManager, err := tlsconfig.NewManager(fallbackTLSProfile)
+		if err != nil {
+			klog.Exitf("unable to build initial webhook TLS config: %v", err)
+		}
+		webHookServer.TLSOpts = append(webHookServer.TLSOpts, tlsManager.Apply)
+
+		if err = (&webhooktls.APIServerReconciler{
+			Client:          mgr.GetClient(),
+			Manager:         tlsManager,
+			FallbackProfile: fallbackTLSProfile,
+		}).SetupWithManager(mgr); err != nil {
+			klog.Exitf("unable to create webhook TLS controller : %v", err)
+		}
+
+		if err = (&performancev1.PerformanceProfile{}).SetupWebhookWithManager(mgr); err != nil {
+			klog.Exitf("unable to create PerformanceProfile v1 webhook : %v", err)
+		}
+		if err = (&performancev2.PerformanceProfile{}).SetupWebhookWithManager(mgr); err != nil {
+			klog.Exitf("unable to create PerformanceProfile v2 webhook : %v", err)
+		}
 	}
 
 	// +kubebuilder:scaffold:builder
 
+	ctx := ctrl.SetupSignalHandler()
+
+	go func() {
+		ticker := time.NewTicker(loggingConfig.FlushFrequency.Duration)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				klog.Flush()
+			}
+		}
+	}()
+
 	klog.Info("Starting the Cmd.")
 
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	err = mgr.Start(ctx)
+
+	// mgr.Elected() is closed once this instance wins the election and
+	// stays closed from then on, so a non-blocking receive here - after
+	// mgr.Start has returned - reliably reports whether we ever led,
+	// without racing a background goroutine against shutdown the way a
+	// concurrent select on mgr.Elected()/ctx.Done() would.
+	var wasElected bool
+	select {
+	case <-mgr.Elected():
+		wasElected = true
+	default:
+	}
+
+	if enableLeaderElection && wasElected {
+		releaseLeaderLease(restConfig, leaderElectionIdentity)
+	}
+
+	if err != nil {
 		klog.Exitf("Manager exited with non-zero code: %v", err)
 	}
 }
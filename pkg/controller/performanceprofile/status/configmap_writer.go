@@ -0,0 +1,79 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+
+	performancev2 "github.com/openshift-kni/performance-addon-operators/api/v2"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// conditionsDataKey is the ConfigMap data key holding the serialized
+// conditions.
+const conditionsDataKey = "conditions"
+
+// ConfigMapWriter serializes conditions into a ConfigMap on a cluster other
+// than the one the profile's components are rendered against - the
+// HyperShift management cluster, where the PerformanceProfile CR does not
+// exist to patch a status onto.
+type ConfigMapWriter struct {
+	// Client talks to the cluster the status ConfigMap is written to.
+	Client    client.Client
+	Namespace string
+}
+
+var _ Writer = &ConfigMapWriter{}
+
+// Update serializes conditions into the status ConfigMap for profile,
+// creating it if needed.
+func (w *ConfigMapWriter) Update(ctx context.Context, profile *performancev2.PerformanceProfile, conditions []metav1.Condition) error {
+	payload, err := yaml.Marshal(conditions)
+	if err != nil {
+		return fmt.Errorf("failed to serialize conditions for PerformanceProfile %q: %w", profile.Name, err)
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      statusConfigMapName(profile.Name),
+			Namespace: w.Namespace,
+		},
+	}
+
+	if _, err := controllerutil.CreateOrUpdate(ctx, w.Client, cm, func() error {
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[conditionsDataKey] = string(payload)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to write status ConfigMap for PerformanceProfile %q: %w", profile.Name, err)
+	}
+
+	return nil
+}
+
+func statusConfigMapName(profileName string) string {
+	return fmt.Sprintf("%s-status", profileName)
+}
@@ -0,0 +1,54 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+
+	performancev2 "github.com/openshift-kni/performance-addon-operators/api/v2"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CRWriter patches conditions directly onto the PerformanceProfile CR's
+// .status, which is today's self-managed behavior.
+type CRWriter struct {
+	Client client.Client
+}
+
+var _ Writer = &CRWriter{}
+
+// Update merges conditions into profile.Status.Conditions via
+// meta.SetStatusCondition and patches the result back to the API server.
+// Merging (rather than overwriting the slice outright) preserves each
+// condition's LastTransitionTime unless its Status actually changed.
+func (w *CRWriter) Update(ctx context.Context, profile *performancev2.PerformanceProfile, conditions []metav1.Condition) error {
+	existing := profile.DeepCopy()
+
+	for _, condition := range conditions {
+		meta.SetStatusCondition(&profile.Status.Conditions, condition)
+	}
+
+	if err := w.Client.Status().Patch(ctx, profile, client.MergeFrom(existing)); err != nil {
+		return fmt.Errorf("failed to patch PerformanceProfile %q status: %w", profile.Name, err)
+	}
+
+	return nil
+}
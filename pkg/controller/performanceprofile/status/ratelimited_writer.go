@@ -0,0 +1,58 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"errors"
+
+	performancev2 "github.com/openshift-kni/performance-addon-operators/api/v2"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// ErrRateLimited is returned by rateLimitedWriter.Update when the limiter
+// denies the write. Callers must requeue rather than treat this as a
+// permanent failure or silently move on - nothing else re-triggers
+// reconciliation for them, so a dropped write that is swallowed here would
+// leave the profile's status permanently stale.
+var ErrRateLimited = errors.New("status update was rate-limited")
+
+// rateLimitedWriter debounces writes to an underlying Writer so a flapping
+// condition source (e.g. an MCP bouncing between Updating and Degraded)
+// can't hot-loop status updates against the API server.
+type rateLimitedWriter struct {
+	next    Writer
+	limiter flowcontrol.RateLimiter
+}
+
+var _ Writer = &rateLimitedWriter{}
+
+// NewRateLimited wraps next so that Update returns ErrRateLimited whenever
+// limiter denies the call, instead of performing it.
+func NewRateLimited(next Writer, limiter flowcontrol.RateLimiter) Writer {
+	return &rateLimitedWriter{next: next, limiter: limiter}
+}
+
+func (w *rateLimitedWriter) Update(ctx context.Context, profile *performancev2.PerformanceProfile, conditions []metav1.Condition) error {
+	if !w.limiter.TryAccept() {
+		return ErrRateLimited
+	}
+
+	return w.next.Update(ctx, profile, conditions)
+}
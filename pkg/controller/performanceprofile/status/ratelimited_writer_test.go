@@ -0,0 +1,77 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	performancev2 "github.com/openshift-kni/performance-addon-operators/api/v2"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+type fakeWriter struct {
+	calls int
+}
+
+func (w *fakeWriter) Update(ctx context.Context, profile *performancev2.PerformanceProfile, conditions []metav1.Condition) error {
+	w.calls++
+	return nil
+}
+
+type fakeLimiter struct {
+	allow bool
+}
+
+func (l *fakeLimiter) TryAccept() bool         { return l.allow }
+func (l *fakeLimiter) Accept()                 {}
+func (l *fakeLimiter) Stop()                   {}
+func (l *fakeLimiter) QPS() float32            { return 0 }
+func (l *fakeLimiter) Saturation() float64     { return 0 }
+func (l *fakeLimiter) SetRate(qps float32)     {}
+func (l *fakeLimiter) Wait(ctx context.Context) error { return nil }
+
+var _ flowcontrol.RateLimiter = &fakeLimiter{}
+
+func TestRateLimitedWriterDropsUpdateWhenDenied(t *testing.T) {
+	next := &fakeWriter{}
+	w := NewRateLimited(next, &fakeLimiter{allow: false})
+
+	profile := &performancev2.PerformanceProfile{}
+	if err := w.Update(context.Background(), profile, nil); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Update() error = %v, want ErrRateLimited", err)
+	}
+	if next.calls != 0 {
+		t.Errorf("next.calls = %d, want 0 when the limiter denies the call", next.calls)
+	}
+}
+
+func TestRateLimitedWriterDelegatesWhenAllowed(t *testing.T) {
+	next := &fakeWriter{}
+	w := NewRateLimited(next, &fakeLimiter{allow: true})
+
+	profile := &performancev2.PerformanceProfile{}
+	if err := w.Update(context.Background(), profile, nil); err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+	if next.calls != 1 {
+		t.Errorf("next.calls = %d, want 1 when the limiter allows the call", next.calls)
+	}
+}
@@ -0,0 +1,35 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package status carries PerformanceProfile status conditions to wherever
+// they need to be surfaced. On a self-managed cluster that is the
+// PerformanceProfile CR itself; on a HyperShift hosted cluster it is a
+// ConfigMap on the management cluster, since the CR does not exist there.
+package status
+
+import (
+	"context"
+
+	performancev2 "github.com/openshift-kni/performance-addon-operators/api/v2"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Writer surfaces a PerformanceProfile's aggregated conditions to its
+// source of truth.
+type Writer interface {
+	Update(ctx context.Context, profile *performancev2.PerformanceProfile, conditions []metav1.Condition) error
+}
@@ -0,0 +1,190 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mcps waits on MachineConfigPool rollouts. It is shared by the
+// PerformanceProfileReconciler, which uses it to compute how long a
+// rollout is expected to take and surface that as a status condition, and
+// by functests/utils/mcps, which wraps it with Ginkgo assertions for the
+// test suite.
+package mcps
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+	statusmcps "github.com/openshift-kni/performance-addon-operators/pkg/status/mcps"
+
+	machineconfigv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultPerNodeTimeout is how long a single node targeted by an MCP
+	// is given to finish applying an update before the MCP is considered
+	// stuck rather than legitimately still rolling out.
+	DefaultPerNodeTimeout = 20 * time.Minute
+
+	// DefaultPollInterval is how often a waited-on condition is re-checked.
+	DefaultPollInterval = 30 * time.Second
+
+	// DefaultProfilePickedUpTimeout bounds WaitForProfilePickedUp.
+	DefaultProfilePickedUpTimeout = 10 * time.Minute
+)
+
+// Options tunes how WaitForCondition times out and how it discovers the
+// nodes targeted by an MCP.
+type Options struct {
+	// PerNodeTimeout is how long to wait for a single targeted node to
+	// finish applying a MachineConfig update. Defaults to
+	// DefaultPerNodeTimeout.
+	PerNodeTimeout time.Duration
+
+	// PollInterval is how often the condition is re-checked. Defaults to
+	// DefaultPollInterval.
+	PollInterval time.Duration
+
+	// Selector overrides the node selector derived from the MCP's own
+	// Spec.NodeSelector, for callers that already know which nodes they
+	// are waiting on.
+	Selector labels.Selector
+}
+
+func (o Options) withDefaults() Options {
+	if o.PerNodeTimeout == 0 {
+		o.PerNodeTimeout = DefaultPerNodeTimeout
+	}
+	if o.PollInterval == 0 {
+		o.PollInterval = DefaultPollInterval
+	}
+	return o
+}
+
+// New creates a new MCP with the given name and node selector.
+func New(mcpName string, nodeSelector map[string]string) *machineconfigv1.MachineConfigPool {
+	return &machineconfigv1.MachineConfigPool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpName,
+			Namespace: metav1.NamespaceNone,
+			Labels:    map[string]string{components.MachineConfigRoleLabelKey: mcpName},
+		},
+		Spec: machineconfigv1.MachineConfigPoolSpec{
+			MachineConfigSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{
+						Key:      components.MachineConfigRoleLabelKey,
+						Operator: "In",
+						Values:   []string{"worker", mcpName},
+					},
+				},
+			},
+			NodeSelector: &metav1.LabelSelector{
+				MatchLabels: nodeSelector,
+			},
+		},
+	}
+}
+
+// WaitForCondition blocks until the MCP with the given name has a
+// condition of the given type with the given status, or ctx/the computed
+// rollout timeout expires.
+func WaitForCondition(ctx context.Context, c client.Client, mcpName string, conditionType machineconfigv1.MachineConfigPoolConditionType, conditionStatus corev1.ConditionStatus, opts Options) error {
+	opts = opts.withDefaults()
+
+	timeout, err := ExpectedRolloutWindow(ctx, c, mcpName, opts)
+	if err != nil {
+		return err
+	}
+
+	return wait.PollUntilContextTimeout(ctx, opts.PollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		status, err := statusmcps.GetConditionStatus(ctx, c, mcpName, conditionType)
+		if err != nil {
+			return false, err
+		}
+		return status == conditionStatus, nil
+	})
+}
+
+// WaitForProfilePickedUp blocks until the MCP with the given name contains
+// the MachineConfig generated for the PerformanceProfile with the given
+// name, or ctx is done, or DefaultProfilePickedUpTimeout expires.
+func WaitForProfilePickedUp(ctx context.Context, c client.Client, mcpName, profileName string, opts Options) error {
+	opts = opts.withDefaults()
+	sourceName := fmt.Sprintf("%s-%s", components.ComponentNamePrefix, profileName)
+
+	return wait.PollUntilContextTimeout(ctx, opts.PollInterval, DefaultProfilePickedUpTimeout, true, func(ctx context.Context) (bool, error) {
+		mcp, err := statusmcps.Get(ctx, c, mcpName)
+		if err != nil {
+			return false, err
+		}
+		for _, source := range mcp.Spec.Configuration.Source {
+			if source.Name == sourceName {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+}
+
+// ExpectedRolloutWindow returns how long a rollout of the MCP with the
+// given name is expected to take, based on the number of nodes it targets.
+// The reconciler surfaces this as the PerformanceProfile's
+// ExpectedRolloutCompletionTime condition so users can tell "the MCP is
+// legitimately still rolling" from "the MCP is stuck".
+func ExpectedRolloutWindow(ctx context.Context, c client.Client, mcpName string, opts Options) (time.Duration, error) {
+	opts = opts.withDefaults()
+
+	selector, err := nodeSelector(ctx, c, mcpName, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := c.List(ctx, nodes, &client.ListOptions{LabelSelector: selector}); err != nil {
+		return 0, fmt.Errorf("failed listing nodes targeted by MCP %q: %w", mcpName, err)
+	}
+	if len(nodes.Items) == 0 {
+		return 0, fmt.Errorf("found no nodes targeted by MCP %q", mcpName)
+	}
+
+	return time.Duration(len(nodes.Items)) * opts.PerNodeTimeout, nil
+}
+
+func nodeSelector(ctx context.Context, c client.Client, mcpName string, opts Options) (labels.Selector, error) {
+	if opts.Selector != nil {
+		return opts.Selector, nil
+	}
+
+	mcp, err := statusmcps.Get(ctx, c, mcpName)
+	if err != nil {
+		return nil, err
+	}
+
+	key, _ := components.GetFirstKeyAndValue(mcp.Spec.NodeSelector.MatchLabels)
+	req, err := labels.NewRequirement(key, selection.Exists, []string{})
+	if err != nil {
+		return nil, fmt.Errorf("failed building node selector for MCP %q: %w", mcpName, err)
+	}
+
+	return labels.NewSelector().Add(*req), nil
+}
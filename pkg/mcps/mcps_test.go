@@ -0,0 +1,82 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mcps
+
+import (
+	"context"
+	"testing"
+
+	machineconfigv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed adding client-go types to scheme: %v", err)
+	}
+	if err := machineconfigv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed adding machineconfigv1 to scheme: %v", err)
+	}
+	return scheme
+}
+
+func TestExpectedRolloutWindowScalesWithNodeCount(t *testing.T) {
+	mcp := New("worker-rt", map[string]string{"node-role.kubernetes.io/worker-rt": ""})
+
+	nodes := []runtime.Object{}
+	for i := 0; i < 3; i++ {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "node" + string(rune('a'+i)),
+				Labels: map[string]string{"node-role.kubernetes.io/worker-rt": ""},
+			},
+		}
+		nodes = append(nodes, node)
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(newScheme(t)).
+		WithRuntimeObjects(append(nodes, mcp)...).
+		Build()
+
+	window, err := ExpectedRolloutWindow(context.Background(), c, mcp.Name, Options{PerNodeTimeout: DefaultPerNodeTimeout})
+	if err != nil {
+		t.Fatalf("ExpectedRolloutWindow returned error: %v", err)
+	}
+
+	want := 3 * DefaultPerNodeTimeout
+	if window != want {
+		t.Errorf("ExpectedRolloutWindow() = %v, want %v", window, want)
+	}
+}
+
+func TestExpectedRolloutWindowNoMatchingNodes(t *testing.T) {
+	mcp := New("worker-rt", map[string]string{"node-role.kubernetes.io/worker-rt": ""})
+
+	c := fake.NewClientBuilder().WithScheme(newScheme(t)).WithRuntimeObjects(mcp).Build()
+
+	if _, err := ExpectedRolloutWindow(context.Background(), c, mcp.Name, Options{}); err == nil {
+		t.Error("expected an error when the MCP targets no nodes")
+	}
+}
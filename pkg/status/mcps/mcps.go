@@ -0,0 +1,78 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mcps reads MachineConfigPool status conditions for aggregation
+// into a PerformanceProfile's own status. It is the controller-facing
+// counterpart of functests/utils/mcps, which wraps the same lookups with
+// Ginkgo assertions for use in the test suite.
+package mcps
+
+import (
+	"context"
+	"fmt"
+
+	machineconfigv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Get returns the MachineConfigPool with the given name.
+func Get(ctx context.Context, c client.Client, name string) (*machineconfigv1.MachineConfigPool, error) {
+	mcp := &machineconfigv1.MachineConfigPool{}
+	key := types.NamespacedName{Name: name, Namespace: metav1.NamespaceNone}
+	if err := c.Get(ctx, key, mcp); err != nil {
+		return nil, fmt.Errorf("failed getting MachineConfigPool %q: %w", name, err)
+	}
+	return mcp, nil
+}
+
+// GetConditionStatus returns the status of the given condition type on the
+// named MachineConfigPool, or corev1.ConditionUnknown if the MCP carries no
+// such condition.
+func GetConditionStatus(ctx context.Context, c client.Client, name string, conditionType machineconfigv1.MachineConfigPoolConditionType) (corev1.ConditionStatus, error) {
+	mcp, err := Get(ctx, c, name)
+	if err != nil {
+		return corev1.ConditionUnknown, err
+	}
+
+	for _, condition := range mcp.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status, nil
+		}
+	}
+
+	return corev1.ConditionUnknown, nil
+}
+
+// GetConditionReason returns the reason of the given condition type on the
+// named MachineConfigPool, or "" if the MCP carries no such condition.
+func GetConditionReason(ctx context.Context, c client.Client, name string, conditionType machineconfigv1.MachineConfigPoolConditionType) (string, error) {
+	mcp, err := Get(ctx, c, name)
+	if err != nil {
+		return "", err
+	}
+
+	for _, condition := range mcp.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Reason, nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,155 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tlsconfig translates the OpenShift apiservers.config.openshift.io
+// TLSSecurityProfile policy into a *tls.Config for the webhook server, and
+// lets that translation be swapped at runtime as the cluster policy changes.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"fmt"
+	"sync/atomic"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/library-go/pkg/crypto"
+)
+
+// tlsPolicy is the subset of a tls.Config that a TLSSecurityProfile
+// actually constrains. Keeping only this instead of a whole *tls.Config
+// means Apply can keep cloning the webhook server's own base config -
+// certificate source included - rather than replacing it outright.
+type tlsPolicy struct {
+	minVersion   uint16
+	cipherSuites []uint16
+}
+
+// Manager holds the current tlsPolicy derived from a TLSSecurityProfile and
+// publishes it through GetConfigForClient, so a profile change can be
+// picked up by future handshakes without restarting the webhook listener.
+type Manager struct {
+	current atomic.Value // tlsPolicy
+}
+
+// NewManager builds a Manager seeded with profile.
+func NewManager(profile *configv1.TLSSecurityProfile) (*Manager, error) {
+	m := &Manager{}
+	if err := m.Set(profile); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Set recomputes the tlsPolicy for profile and publishes it.
+func (m *Manager) Set(profile *configv1.TLSSecurityProfile) error {
+	policy, err := policyFromProfile(profile)
+	if err != nil {
+		return err
+	}
+	m.current.Store(policy)
+	return nil
+}
+
+// Apply is a controller-runtime webhook.Server TLSOpts function. cfg is the
+// server's base config, already carrying its certificate source
+// (GetCertificate) from an earlier TLSOpts step; Apply clones it and wires
+// GetConfigForClient to hand out a copy with the most recently Set
+// MinVersion/CipherSuites, preserving that certificate source rather than
+// replacing the whole handshake config.
+func (m *Manager) Apply(cfg *tls.Config) {
+	base := cfg.Clone()
+	cfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		policy, ok := m.current.Load().(tlsPolicy)
+		if !ok {
+			return base, nil
+		}
+		current := base.Clone()
+		current.MinVersion = policy.minVersion
+		current.CipherSuites = policy.cipherSuites
+		return current, nil
+	}
+}
+
+// policyFromProfile translates an OpenShift TLSSecurityProfile into the
+// minimum version and cipher suites it constrains.
+func policyFromProfile(profile *configv1.TLSSecurityProfile) (tlsPolicy, error) {
+	spec, err := profileSpec(profile)
+	if err != nil {
+		return tlsPolicy{}, err
+	}
+
+	minVersion, err := crypto.TLSVersion(string(spec.MinTLSVersion))
+	if err != nil {
+		return tlsPolicy{}, fmt.Errorf("invalid minimum TLS version %q: %w", spec.MinTLSVersion, err)
+	}
+
+	cipherSuites, err := cipherSuiteIDs(spec.Ciphers)
+	if err != nil {
+		return tlsPolicy{}, fmt.Errorf("invalid cipher suites for TLSSecurityProfile: %w", err)
+	}
+
+	return tlsPolicy{minVersion: minVersion, cipherSuites: cipherSuites}, nil
+}
+
+// cipherSuiteIDs resolves OpenSSL-style cipher names to IANA suite IDs.
+// Names that crypto.OpenSSLToIANACipherSuites does not map are skipped. It
+// returns an error instead of panicking when a mapped name is not a suite
+// Go knows, or when names is non-empty and none of it can be mapped - a
+// malformed cluster TLSSecurityProfile must fail reconciliation, not crash
+// the operator.
+func cipherSuiteIDs(names []string) ([]uint16, error) {
+	idsByName := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		idsByName[suite.Name] = suite.ID
+	}
+	for _, suite := range tls.InsecureCipherSuites() {
+		idsByName[suite.Name] = suite.ID
+	}
+
+	ianaNames := crypto.OpenSSLToIANACipherSuites(names)
+	if len(names) > 0 && len(ianaNames) == 0 {
+		return nil, fmt.Errorf("unknown cipher suites %q", names)
+	}
+	ids := make([]uint16, 0, len(ianaNames))
+	for _, name := range ianaNames {
+		id, ok := idsByName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown cipher suite %q", name)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func profileSpec(profile *configv1.TLSSecurityProfile) (*configv1.TLSProfileSpec, error) {
+	if profile == nil || profile.Type == "" {
+		return configv1.TLSProfiles[configv1.TLSProfileIntermediateType], nil
+	}
+
+	if profile.Type == configv1.TLSProfileCustomType {
+		if profile.Custom == nil {
+			return nil, fmt.Errorf("TLSSecurityProfile type is Custom but Custom is nil")
+		}
+		return &profile.Custom.TLSProfileSpec, nil
+	}
+
+	spec, ok := configv1.TLSProfiles[profile.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown TLSSecurityProfile type %q", profile.Type)
+	}
+	return spec, nil
+}
@@ -0,0 +1,99 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/library-go/pkg/crypto"
+)
+
+func TestPolicyFromProfileDefaultsToIntermediate(t *testing.T) {
+	policy, err := policyFromProfile(nil)
+	if err != nil {
+		t.Fatalf("policyFromProfile(nil) returned error: %v", err)
+	}
+
+	intermediate := configv1.TLSProfiles[configv1.TLSProfileIntermediateType]
+	wantMinVersion, err := crypto.TLSVersion(string(intermediate.MinTLSVersion))
+	if err != nil {
+		t.Fatalf("failed resolving Intermediate min version: %v", err)
+	}
+
+	if policy.minVersion != wantMinVersion {
+		t.Errorf("minVersion = %v, want %v", policy.minVersion, wantMinVersion)
+	}
+	if len(policy.cipherSuites) == 0 {
+		t.Error("expected cipherSuites to be populated for the Intermediate profile")
+	}
+}
+
+func TestPolicyFromProfileCustom(t *testing.T) {
+	profile := &configv1.TLSSecurityProfile{
+		Type: configv1.TLSProfileCustomType,
+		Custom: &configv1.CustomTLSProfile{
+			TLSProfileSpec: configv1.TLSProfileSpec{
+				MinTLSVersion: configv1.VersionTLS13,
+				Ciphers:       []string{"TLS_AES_128_GCM_SHA256"},
+			},
+		},
+	}
+
+	policy, err := policyFromProfile(profile)
+	if err != nil {
+		t.Fatalf("policyFromProfile(Custom) returned error: %v", err)
+	}
+	if policy.minVersion != tls.VersionTLS13 {
+		t.Errorf("minVersion = %v, want tls.VersionTLS13", policy.minVersion)
+	}
+}
+
+func TestPolicyFromProfileCustomRequiresSpec(t *testing.T) {
+	profile := &configv1.TLSSecurityProfile{Type: configv1.TLSProfileCustomType}
+
+	if _, err := policyFromProfile(profile); err == nil {
+		t.Error("expected an error for a Custom profile with a nil Custom spec")
+	}
+}
+
+func TestCipherSuiteIDsRejectsUnknownName(t *testing.T) {
+	if _, err := cipherSuiteIDs([]string{"not-a-real-cipher"}); err == nil {
+		t.Error("expected an error for an unrecognized cipher name instead of a panic")
+	}
+}
+
+func TestManagerApplyPreservesCertificateSource(t *testing.T) {
+	m, err := NewManager(nil)
+	if err != nil {
+		t.Fatalf("NewManager returned error: %v", err)
+	}
+
+	getCertificate := func(*tls.ClientHelloInfo) (*tls.Certificate, error) { return nil, nil }
+	base := &tls.Config{GetCertificate: getCertificate}
+
+	m.Apply(base)
+
+	got, err := base.GetConfigForClient(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatalf("GetConfigForClient returned error: %v", err)
+	}
+	if got.GetCertificate == nil {
+		t.Error("expected the config handed out by GetConfigForClient to keep the base GetCertificate")
+	}
+}